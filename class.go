@@ -0,0 +1,81 @@
+package errprops
+
+import (
+	stderrors "errors"
+)
+
+// classKey tags a PropError with the Class it was created or wrapped through, so Find can
+// locate it later without inventing a string key for the discriminator.
+var classKey = NewKey[*Class]("class")
+
+// Class is a named kind of error that can be created or wrapped with New/Wrap and located
+// within a chain with Find – analogous to a sentinel error, but reusable across many error
+// values and locatable even when buried under other annotations. A typical use is reacting
+// to a kind of error from deep in a call chain, e.g. "if this chain contains a Retryable
+// error, get its retry-after property and reschedule":
+//
+//	var Retryable = errprops.NewClass("retryable")
+//
+//	if retryable, ok := errprops.Find(err, Retryable); ok {
+//		after, _ := errprops.Get(retryable, "retry-after")
+//		scheduleRetry(after.(time.Duration))
+//	}
+type Class struct {
+	name string
+}
+
+// NewClass returns a new Class. name is used only to make the Class printable for
+// debugging; it has no bearing on the Class's identity.
+func NewClass(name string) *Class {
+	return &Class{name}
+}
+
+func (c *Class) String() string {
+	return c.name
+}
+
+// New returns a PropError of this Class, wrapping a new error with the given message, with
+// kvs attached via WithValue as alternating key/value pairs.
+func (c *Class) New(msg string, kvs ...interface{}) PropError {
+	return c.tag(From(stderrors.New(msg)), kvs)
+}
+
+// Wrap returns a PropError of this Class wrapping err, with kvs attached via WithValue as
+// alternating key/value pairs.
+func (c *Class) Wrap(err error, kvs ...interface{}) PropError {
+	return c.tag(From(err), kvs)
+}
+
+func (c *Class) tag(pe PropError, kvs []interface{}) PropError {
+	if len(kvs)%2 != 0 {
+		panic("errprops: odd number of key/value arguments")
+	}
+
+	pe = pe.WithValue(classKey, c)
+	for i := 0; i < len(kvs); i += 2 {
+		pe = pe.WithValue(kvs[i], kvs[i+1])
+	}
+	return pe
+}
+
+// Find walks err's chain, the same way Get does, looking for a PropError created or wrapped
+// through class. It returns the first one found, so callers can Get further properties
+// attached alongside the Class tag.
+func Find(err error, class *Class) (PropError, bool) {
+	for err != nil {
+		if pe, ok := err.(PropError); ok {
+			if val, ok := pe.Get(classKey); ok {
+				if cls, ok := val.(*Class); ok && cls == class {
+					return pe, true
+				}
+			}
+		}
+
+		e, ok := err.(hasCause)
+		if !ok {
+			break
+		}
+		err = e.Cause()
+	}
+	return nil, false
+}
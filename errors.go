@@ -39,9 +39,9 @@ See the Examples for more.
 package errprops
 
 import (
+	stderrors "errors"
 	"fmt"
-
-	"github.com/pkg/errors"
+	"reflect"
 )
 
 // Specified by github.com/pkg/errors
@@ -49,15 +49,19 @@ type hasCause interface {
 	Cause() error
 }
 
-// Specified by github.com/pkg/errors
 type hasStacktrace interface {
-	Stacktrace() errors.Stacktrace
+	Stacktrace() Stacktrace
 }
 
 type hasProps interface {
 	// Returns the value associated with the given key on the current error.
 	// Should *NOT* recurse into the error's cause, if it has one.
 	Get(key interface{}) (value interface{}, ok bool)
+
+	// Calls fn with every key/value pair set directly on the current error, most-recently-set
+	// first. Should *NOT* recurse into the error's cause, if it has one. Stops early if fn
+	// returns false.
+	RangeOwn(fn func(key, value interface{}) bool)
 }
 
 // An implementation of the standard error interface that can set key/value pairs.
@@ -81,6 +85,11 @@ type PropError interface {
 	// Does not modify the current PropError.
 	WithValue(key, value interface{}) PropError
 
+	// Reports whether this error itself – not its cause – is target, per errors.Is
+	// semantics. Package-level Is walks the cause chain, calling this at each PropError
+	// it encounters, so that wrapping an error in properties never hides its identity.
+	Is(target error) bool
+
 	// If the wrapped error implements fmt.Formatter, this method should delegate directly
 	// to it.
 	formatBaseError(f fmt.State, c rune)
@@ -94,7 +103,7 @@ type PropError interface {
 //			WithValue("someKey", someValue).
 // 			WithValue("otherKey", otherValue)
 func From(err error) PropError {
-	return baseError{err}
+	return baseError{withStackIfMissing(err)}
 }
 
 // Get returns the value associated with key using the following rules to resolve key:
@@ -127,6 +136,105 @@ func GetOptional(err error, key interface{}) interface{} {
 	return nil
 }
 
+// AppendLogFields calls fn once for every property set anywhere in err's chain, in the
+// same override order Get uses: if a key is set at more than one level, only the outermost
+// value is passed to fn. This lets structured loggers (zap, zerolog, logrus, …) consume
+// properties directly as fields without errprops allocating a map on their behalf. Values for
+// keys with a registered Formatter (see RegisterFormatter/Redact) are passed through it first,
+// so redacted properties don't leak into structured output just because it bypasses Format.
+func AppendLogFields(err error, fn func(key, value interface{})) {
+	seen := make(map[interface{}]struct{})
+
+	for err != nil {
+		if e, ok := err.(hasProps); ok {
+			e.RangeOwn(func(key, value interface{}) bool {
+				if _, ok := seen[key]; !ok {
+					seen[key] = struct{}{}
+					fn(key, formattedValue(key, value))
+				}
+				return true
+			})
+		}
+
+		e, ok := err.(hasCause)
+		if !ok {
+			break
+		}
+		err = e.Cause()
+	}
+}
+
+// Properties returns every property set anywhere in err's chain as a map, applying the same
+// override rule as Get: if a key is set at more than one level, the outermost value wins.
+// It's intended for audit/log pipelines that want to dump everything ever attached to an
+// error without knowing the keys ahead of time; see AppendLogFields to avoid the allocation.
+func Properties(err error) map[interface{}]interface{} {
+	props := make(map[interface{}]interface{})
+	AppendLogFields(err, func(key, value interface{}) {
+		props[key] = value
+	})
+	return props
+}
+
+// Is reports whether any error in err's chain matches target, the same way the standard
+// library's errors.Is does. It walks the chain the same way Get does – via the hasCause
+// interface – so property annotations never get in the way of identity checks, even when
+// the underlying errors predate Go 1.13 and don't implement Unwrap. Errors lower in the
+// chain that do implement Unwrap are still handled correctly, via a fallback to errors.Is.
+func Is(err, target error) bool {
+	if target == nil {
+		return err == target
+	}
+	for err != nil {
+		if err == target {
+			return true
+		}
+		if e, ok := err.(PropError); ok && e.Is(target) {
+			return true
+		}
+		if e, ok := err.(hasCause); ok {
+			err = e.Cause()
+			continue
+		}
+		return stderrors.Is(err, target)
+	}
+	return false
+}
+
+// As finds the first error in err's chain that matches target, the same way the standard
+// library's errors.As does, and if found, sets target to that error value and returns true.
+// It walks the chain the same way Get does – via the hasCause interface – so typed target
+// extraction isn't blocked by property-annotated wrappers. As panics if target is not a
+// non-nil pointer to either a type that implements error, or to any interface type.
+func As(err error, target interface{}) bool {
+	if target == nil {
+		panic("errprops: target cannot be nil")
+	}
+	val := reflect.ValueOf(target)
+	if val.Kind() != reflect.Ptr || val.IsNil() {
+		panic("errprops: target must be a non-nil pointer")
+	}
+	targetType := val.Type().Elem()
+	if targetType.Kind() != reflect.Interface && !targetType.Implements(errorType) {
+		panic("errprops: *target must be interface or implement error")
+	}
+
+	for err != nil {
+		if errVal := reflect.ValueOf(err); errVal.Type().AssignableTo(targetType) {
+			val.Elem().Set(errVal)
+			return true
+		}
+		if e, ok := err.(hasCause); ok {
+			err = e.Cause()
+			continue
+		}
+		return stderrors.As(err, target)
+	}
+	return false
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
 // Implementation of PropError that just delegates most calls to the underlying error
 // if it supports them.
 type baseError struct {
@@ -140,10 +248,13 @@ func (e baseError) Cause() error {
 	return nil
 }
 
-func (e baseError) Stacktrace() errors.Stacktrace {
+func (e baseError) Stacktrace() Stacktrace {
 	if e, ok := e.error.(hasStacktrace); ok {
 		return e.Stacktrace()
 	}
+	if st, ok := foreignStacktrace(e.error); ok {
+		return st
+	}
 	return nil
 }
 
@@ -154,6 +265,28 @@ func (e baseError) Get(key interface{}) (interface{}, bool) {
 	return nil, false
 }
 
+func (e baseError) RangeOwn(fn func(key, value interface{}) bool) {
+	if e, ok := e.error.(hasProps); ok {
+		e.RangeOwn(fn)
+	}
+}
+
+// Unwrap returns the error this PropError decorates, so that the standard library's
+// errors.Unwrap/Is/As can see through the property annotation.
+func (e baseError) Unwrap() error {
+	return e.error
+}
+
+func (e baseError) Is(target error) bool {
+	if e.error == target {
+		return true
+	}
+	if e, ok := e.error.(interface{ Is(error) bool }); ok {
+		return e.Is(target)
+	}
+	return false
+}
+
 func (e baseError) WithValue(key, value interface{}) PropError {
 	return &keyValueError{e, key, value}
 }
@@ -183,11 +316,28 @@ func (e *keyValueError) Get(key interface{}) (interface{}, bool) {
 	return e.PropError.Get(key)
 }
 
+func (e *keyValueError) RangeOwn(fn func(key, value interface{}) bool) {
+	if !fn(e.key, e.value) {
+		return
+	}
+	e.PropError.RangeOwn(fn)
+}
+
 func (e *keyValueError) WithValue(key, value interface{}) PropError {
 	// Override here so the wrapped error is this object, not the embedded PropError.
 	return &keyValueError{e, key, value}
 }
 
+// Unwrap returns the PropError this key/value pair is attached to, so the standard
+// library's errors.Unwrap/Is/As can see through this node's property.
+func (e *keyValueError) Unwrap() error {
+	return e.PropError
+}
+
+func (e *keyValueError) Is(target error) bool {
+	return e.PropError.Is(target)
+}
+
 func (e *keyValueError) formatBaseError(f fmt.State, c rune) {
 	e.PropError.formatBaseError(f, c)
 }
@@ -209,7 +359,11 @@ func (e *keyValueError) formatInner(f fmt.State, c rune) {
 	}
 	format = string(append([]rune(format), c))
 
-	fmt.Fprintf(f, format+"="+format, e.key, e.value)
+	if formatter := lookupFormatter(e.key); formatter != nil {
+		fmt.Fprintf(f, format+"=%s", e.key, formatter(e.key, e.value, c, f.Flag('+'), f.Flag('#')))
+	} else {
+		fmt.Fprintf(f, format+"="+format, e.key, e.value)
+	}
 
 	if e, ok := e.PropError.(*keyValueError); ok {
 		fmt.Fprint(f, ",")
@@ -1,6 +1,8 @@
 package errprops
 
 import (
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"io"
 	"strings"
@@ -192,6 +194,205 @@ func TestGetRootCauseHasProp(t *testing.T) {
 	assertHasProp(t, err, "foo", "bar")
 }
 
+func TestIs(t *testing.T) {
+	sentinel := stderrors.New("sentinel")
+
+	propErr := From(sentinel).WithValue("key", "value")
+	assert.True(t, Is(propErr, sentinel))
+	assert.False(t, Is(propErr, stderrors.New("sentinel")))
+
+	wrapped := From(errors.Wrap(propErr, "wrapped")).WithValue("outer", "value")
+	assert.True(t, Is(wrapped, sentinel))
+
+	assert.False(t, Is(wrapped, nil))
+	assert.True(t, Is(nil, nil))
+}
+
+func TestAs(t *testing.T) {
+	cause := &customError{msg: "custom"}
+	err := From(errors.Wrap(cause, "wrapped")).WithValue("key", "value")
+
+	var target *customError
+	assert.True(t, As(err, &target))
+	assert.Equal(t, cause, target)
+
+	var notFound *otherError
+	assert.False(t, As(err, &notFound))
+}
+
+type customError struct{ msg string }
+
+func (e *customError) Error() string { return e.msg }
+
+type otherError struct{ msg string }
+
+func (e *otherError) Error() string { return e.msg }
+
+func TestProperties(t *testing.T) {
+	rootCause := From(errors.New("root cause")).
+		WithValue("key", "rootValue").
+		WithValue("rootKey", "rootValue")
+
+	wrapped := From(errors.Wrap(rootCause, "wrapped")).
+		WithValue("key", "wrappedValue")
+
+	props := Properties(wrapped)
+	assert.Equal(t, map[interface{}]interface{}{
+		"key":     "wrappedValue",
+		"rootKey": "rootValue",
+	}, props)
+}
+
+func TestAppendLogFields(t *testing.T) {
+	rootCause := From(errors.New("root cause")).WithValue("rootKey", "rootValue")
+	wrapped := From(errors.Wrap(rootCause, "wrapped")).WithValue("wrappedKey", "wrappedValue")
+
+	var fields []interface{}
+	AppendLogFields(wrapped, func(key, value interface{}) {
+		fields = append(fields, key, value)
+	})
+
+	assert.Equal(t, []interface{}{"wrappedKey", "wrappedValue", "rootKey", "rootValue"}, fields)
+}
+
+func TestKeyValueErrorMarshalJSON(t *testing.T) {
+	rootCause := From(errors.New("root cause")).WithValue("rootKey", "rootValue")
+	wrapped := From(errors.Wrap(rootCause, "wrapped")).WithValue("key", "wrappedValue")
+
+	b, err := json.Marshal(wrapped)
+	assert.NoError(t, err)
+
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+
+	assert.Equal(t, "wrapped: root cause", decoded["message"])
+	assert.Equal(t, map[string]interface{}{"key": "wrappedValue"}, decoded["properties"])
+
+	cause, ok := decoded["cause"].(map[string]interface{})
+	assert.True(t, ok)
+	assert.Equal(t, "root cause", cause["message"])
+	assert.Equal(t, map[string]interface{}{"rootKey": "rootValue"}, cause["properties"])
+}
+
+func TestKey(t *testing.T) {
+	type state struct{ Count int }
+
+	countKey := NewKey[int]("count")
+	stateKey := NewKey[state]("state")
+
+	err := countKey.Set(errors.New("hello"), 42)
+	err = stateKey.Set(err, state{Count: 42})
+
+	count, ok := countKey.Get(err)
+	assert.True(t, ok)
+	assert.Equal(t, 42, count)
+
+	st, ok := stateKey.Get(err)
+	assert.True(t, ok)
+	assert.Equal(t, state{Count: 42}, st)
+
+	otherCountKey := NewKey[int]("count")
+	_, ok = otherCountKey.Get(err)
+	assert.False(t, ok, "a distinct Key with the same name must not collide")
+
+	_, ok = NewKey[string]("missing").Get(err)
+	assert.False(t, ok)
+}
+
+func TestClassFind(t *testing.T) {
+	retryable := NewClass("retryable")
+	notRetryable := NewClass("not-retryable")
+
+	classed := retryable.New("rate limited", "retry-after", 5)
+	wrapped := From(errors.Wrap(classed, "request failed")).WithValue("url", "/foo")
+
+	found, ok := Find(wrapped, retryable)
+	assert.True(t, ok)
+	assertHasPropOnSelf(t, found, "retry-after", 5)
+
+	_, ok = Find(wrapped, notRetryable)
+	assert.False(t, ok)
+
+	_, ok = Find(errors.New("plain"), retryable)
+	assert.False(t, ok)
+}
+
+func TestClassWrap(t *testing.T) {
+	retryable := NewClass("retryable")
+	cause := errors.New("boom")
+
+	err := retryable.Wrap(cause, "retry-after", 1)
+	assert.EqualError(t, err, "boom")
+
+	found, ok := Find(err, retryable)
+	assert.True(t, ok)
+	assert.Same(t, err, found)
+}
+
+func TestRedact(t *testing.T) {
+	Redact("test-password")
+
+	err := From(errors.New("login failed")).WithValue("test-password", "hunter2")
+
+	assert.Equal(t, "[test-password=***] login failed", fmt.Sprintf("%v", err))
+}
+
+func TestRedactAppliesToStructuredOutput(t *testing.T) {
+	Redact("test-token")
+
+	err := From(errors.New("login failed")).WithValue("test-token", "hunter2")
+
+	props := Properties(err)
+	assert.Equal(t, "***", props["test-token"])
+
+	b, jerr := json.Marshal(err)
+	assert.NoError(t, jerr)
+
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, "***", decoded["properties"].(map[string]interface{})["test-token"])
+}
+
+func TestRegisterFormatter(t *testing.T) {
+	RegisterFormatter(
+		func(key interface{}) bool { return key == "test-duration" },
+		func(key, value interface{}, verb rune, flagPlus, flagHash bool) string {
+			return value.(time.Duration).String() + "!"
+		},
+	)
+
+	err := From(errors.New("slow")).WithValue("test-duration", 2*time.Second)
+
+	assert.Equal(t, "[test-duration=2s!] slow", fmt.Sprintf("%v", err))
+}
+
+func TestFromStdlibErrorHasStacktrace(t *testing.T) {
+	err := From(stderrors.New("plain"))
+
+	stacktrace := err.Stacktrace()
+	assert.NotNil(t, stacktrace)
+	assert.NotEmpty(t, stacktrace)
+}
+
+func TestWithMessage(t *testing.T) {
+	cause := stderrors.New("cause")
+	err := WithMessage(cause, "context")
+
+	assert.EqualError(t, err, "context: cause")
+	assert.True(t, Is(err, cause))
+	assert.NotEmpty(t, err.Stacktrace())
+
+	assert.Nil(t, WithMessage(nil, "context"))
+}
+
+func TestWithMessagef(t *testing.T) {
+	cause := stderrors.New("cause")
+	err := WithMessagef(cause, "context %d", 42)
+
+	assert.EqualError(t, err, "context 42: cause")
+	assert.Nil(t, WithMessagef(nil, "context %d", 42))
+}
+
 func assertHasPropOnSelf(t *testing.T, err PropError, key, wantVal interface{}) {
 	val, ok := err.Get(key)
 	assert.True(t, ok)
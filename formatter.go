@@ -0,0 +1,72 @@
+package errprops
+
+import "sync"
+
+// Formatter renders the value of a property for Format, in place of the default
+// "%v"/"%+v"/"%#v" rendering. verb, flagPlus and flagHash mirror the format directive
+// Format was called with, so a Formatter can mimic the default rendering's verbosity if it
+// wants to.
+type Formatter func(key, value interface{}, verb rune, flagPlus, flagHash bool) string
+
+type registeredFormatter struct {
+	matches func(key interface{}) bool
+	format  Formatter
+}
+
+var (
+	formattersMu sync.RWMutex
+	formatters   []registeredFormatter
+)
+
+// RegisterFormatter makes formatter responsible for rendering the value of any property
+// whose key matches keyMatcher, across all errors in the process – useful for keys like
+// tokens or PII that should never be printed verbatim, wherever they show up. If more than
+// one registered matcher matches a key, the most recently registered one wins.
+func RegisterFormatter(keyMatcher func(key interface{}) bool, formatter Formatter) {
+	formattersMu.Lock()
+	defer formattersMu.Unlock()
+	formatters = append(formatters, registeredFormatter{keyMatcher, formatter})
+}
+
+func lookupFormatter(key interface{}) Formatter {
+	formattersMu.RLock()
+	defer formattersMu.RUnlock()
+	for i := len(formatters) - 1; i >= 0; i-- {
+		if formatters[i].matches(key) {
+			return formatters[i].format
+		}
+	}
+	return nil
+}
+
+// formattedValue returns value as a registered Formatter would render it for key, so that
+// structured outputs (Properties, AppendLogFields, MarshalJSON) honor RegisterFormatter/Redact
+// the same way Format does, instead of only affecting %v-style printing. If no Formatter is
+// registered for key, value is returned unchanged.
+func formattedValue(key, value interface{}) interface{} {
+	if formatter := lookupFormatter(key); formatter != nil {
+		return formatter(key, value, 'v', false, false)
+	}
+	return value
+}
+
+// Redact registers a Formatter that renders the values of the given keys as "***" instead of
+// their real value wherever errprops renders properties: Format, Properties, AppendLogFields
+// and MarshalJSON. Get and GetOptional still return the real value. Use it for properties like tokens,
+// passwords, or other PII that might otherwise end up verbatim in a log or audit record.
+func Redact(keys ...interface{}) {
+	redacted := make(map[interface{}]struct{}, len(keys))
+	for _, key := range keys {
+		redacted[key] = struct{}{}
+	}
+
+	RegisterFormatter(
+		func(key interface{}) bool {
+			_, ok := redacted[key]
+			return ok
+		},
+		func(key, value interface{}, verb rune, flagPlus, flagHash bool) string {
+			return "***"
+		},
+	)
+}
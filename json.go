@@ -0,0 +1,60 @@
+package errprops
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// jsonError is the structure keyValueError.MarshalJSON writes. Cause is itself either a
+// *jsonError-shaped value (if the cause is a PropError) or a plain {"message": …} object,
+// so the full chain renders as a tree of nested frames suitable for audit logging.
+type jsonError struct {
+	Message    string                 `json:"message"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+	Cause      interface{}            `json:"cause,omitempty"`
+}
+
+// MarshalJSON renders this error and its properties, plus everything in its cause chain, as
+// a tree of {"message", "properties", "cause"} objects. Properties are recorded against the
+// frame they were set on – not flattened – so overriding a key at an outer frame (see
+// Example_overriding) doesn't erase the inner frame's original value from the audit record.
+// Values for keys with a registered Formatter (see RegisterFormatter/Redact) are rendered
+// through it, so a redacted key doesn't come out in plaintext just because it's marshaled
+// instead of formatted.
+func (e *keyValueError) MarshalJSON() ([]byte, error) {
+	props := make(map[string]interface{})
+	e.RangeOwn(func(key, value interface{}) bool {
+		k := fmt.Sprint(key)
+		if _, ok := props[k]; !ok {
+			props[k] = formattedValue(key, value)
+		}
+		return true
+	})
+
+	return json.Marshal(jsonError{
+		Message:    e.Error(),
+		Properties: props,
+		Cause:      causeJSON(e.Cause()),
+	})
+}
+
+// causeJSON returns a JSON-marshalable representation of err so MarshalJSON can recurse into
+// causes that are themselves property-annotated, skipping over any intermediate causes that
+// aren't (e.g. a plain pkg/errors.Wrap/WithMessage frame sitting between two PropErrors)
+// rather than stopping at the first one and losing the properties beneath it. The walk
+// follows Cause only, the same way Get does: an error without a Cause method (such as one
+// from fmt.Errorf) ends it and is rendered as a plain {"message": …} object.
+func causeJSON(err error) interface{} {
+	for err != nil {
+		if _, ok := err.(json.Marshaler); ok {
+			return err
+		}
+
+		e, ok := err.(hasCause)
+		if !ok {
+			return jsonError{Message: err.Error()}
+		}
+		err = e.Cause()
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package errprops
+
+// Key is a typed, identity-comparable key for a single property on a PropError, layered
+// over the existing interface{}-keyed WithValue/Get machinery. Two Keys are never equal
+// unless they're the same *Key[T] value, which sidesteps the string-collision risk visible
+// in TestWithValue – no other package can accidentally create a colliding Key.
+//
+// Construct one with NewKey and keep it as a package-level variable, the same way
+// context.Value keys are conventionally declared:
+//
+//	var userIDKey = errprops.NewKey[int]("userID")
+//
+//	func DoThing(userID int) error {
+//		if err := do(); err != nil {
+//			return userIDKey.Set(err, userID)
+//		}
+//		return nil
+//	}
+type Key[T any] struct {
+	name string
+}
+
+// NewKey returns a new Key for values of type T. name is used only to make the Key
+// printable for debugging; it has no bearing on the Key's identity.
+func NewKey[T any](name string) *Key[T] {
+	return &Key[T]{name}
+}
+
+func (k *Key[T]) String() string {
+	return k.name
+}
+
+// Set returns a copy of err with val attached under k. It's equivalent to
+// From(err).WithValue(k, val), but gives callers static typing on the way in.
+func (k *Key[T]) Set(err error, val T) PropError {
+	return From(err).WithValue(k, val)
+}
+
+// Get returns the value set under k anywhere in err's chain, following the same override
+// rules as the package-level Get: the outermost value set under k wins. The bool result is
+// false if k was never set, or if the value stored under it isn't a T.
+func (k *Key[T]) Get(err error) (T, bool) {
+	val, ok := Get(err, k)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	t, ok := val.(T)
+	return t, ok
+}
@@ -0,0 +1,37 @@
+package errprops
+
+import "fmt"
+
+// withMessage decorates an error with a message, the same way github.com/pkg/errors's
+// internal withMessage type does, without requiring that package.
+type withMessage struct {
+	cause error
+	msg   string
+}
+
+func (w *withMessage) Error() string { return w.msg + ": " + w.cause.Error() }
+func (w *withMessage) Cause() error  { return w.cause }
+func (w *withMessage) Unwrap() error { return w.cause }
+
+// WithMessage returns a PropError that prepends msg to err's message, the same way
+// github.com/pkg/errors.Wrap does, but without requiring that package: combined with the
+// stack capture From already does internally, this gives callers properties, message
+// wrapping, and a stack trace in one call.
+//
+// If err is nil, WithMessage returns nil.
+func WithMessage(err error, msg string) PropError {
+	if err == nil {
+		return nil
+	}
+	return From(&withMessage{cause: err, msg: msg})
+}
+
+// WithMessagef is the same as WithMessage, but formats msg with fmt.Sprintf.
+//
+// If err is nil, WithMessagef returns nil.
+func WithMessagef(err error, format string, args ...interface{}) PropError {
+	if err == nil {
+		return nil
+	}
+	return WithMessage(err, fmt.Sprintf(format, args...))
+}
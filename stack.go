@@ -0,0 +1,129 @@
+package errprops
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+)
+
+// Frame is a single program counter captured in a Stacktrace.
+type Frame uintptr
+
+func (f Frame) pc() uintptr { return uintptr(f) - 1 }
+
+// Format formats the frame: %s is the source file, %d the source line, %v is "%s:%d". %+s
+// additionally prints the function name on its own line, the same convention pkg/errors
+// uses.
+func (f Frame) Format(s fmt.State, verb rune) {
+	fn := runtime.FuncForPC(f.pc())
+	if fn == nil {
+		fmt.Fprint(s, "unknown")
+		return
+	}
+	file, line := fn.FileLine(f.pc())
+
+	switch verb {
+	case 's':
+		if s.Flag('+') {
+			fmt.Fprintf(s, "%s\n\t%s", fn.Name(), file)
+			return
+		}
+		fmt.Fprint(s, file)
+	case 'd':
+		fmt.Fprint(s, line)
+	case 'v':
+		f.Format(s, 's')
+		fmt.Fprint(s, ":")
+		f.Format(s, 'd')
+	}
+}
+
+// Stacktrace is a stack of Frames, innermost (newest) first.
+type Stacktrace []Frame
+
+func (st Stacktrace) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			for _, f := range st {
+				fmt.Fprint(s, "\n")
+				f.Format(s, verb)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		fmt.Fprint(s, []Frame(st))
+	}
+}
+
+// withStack decorates an error with a stack trace captured at the point it was created, the
+// same way github.com/pkg/errors.WithStack does. It exists so that From can give errors a
+// real Stacktrace() even when they didn't come from pkg/errors – e.g. stdlib errors.New or
+// fmt.Errorf – which makes pkg/errors an optional interop layer rather than a dependency
+// required for useful stack traces.
+type withStack struct {
+	error
+	frames Stacktrace
+}
+
+func newWithStack(err error) *withStack {
+	const depth = 32
+	var pcs [depth]uintptr
+	// Skip runtime.Callers, newWithStack, and withStackIfMissing.
+	n := runtime.Callers(3, pcs[:])
+
+	frames := make(Stacktrace, n)
+	for i, pc := range pcs[:n] {
+		frames[i] = Frame(pc)
+	}
+	return &withStack{err, frames}
+}
+
+func (w *withStack) Cause() error { return w.error }
+
+func (w *withStack) Unwrap() error { return w.error }
+
+func (w *withStack) Stacktrace() Stacktrace { return w.frames }
+
+// withStackIfMissing wraps err in a withStack, unless err itself already carries a stack
+// trace – either via hasStacktrace, or via foreignStacktrace's interop with packages like
+// github.com/pkg/errors. Only err itself is checked: a stack trace further down its cause
+// chain does not stop a new one from being captured.
+func withStackIfMissing(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(hasStacktrace); ok {
+		return err
+	}
+	if _, ok := foreignStacktrace(err); ok {
+		return err
+	}
+	return newWithStack(err)
+}
+
+// foreignStacktrace recognizes the de facto standard shape used by github.com/pkg/errors and
+// compatible packages for stack-trace-bearing errors – a no-arg method named StackTrace
+// returning a slice whose elements are convertible to uintptr – using reflection, so errprops
+// can interoperate with them without requiring that package as a dependency.
+func foreignStacktrace(err error) (Stacktrace, bool) {
+	if err == nil {
+		return nil, false
+	}
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return nil, false
+	}
+
+	out := m.Call(nil)[0]
+	if out.Kind() != reflect.Slice || out.Type().Elem().Kind() != reflect.Uintptr {
+		return nil, false
+	}
+
+	frames := make(Stacktrace, out.Len())
+	for i := range frames {
+		frames[i] = Frame(out.Index(i).Uint())
+	}
+	return frames, true
+}